@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/arjunbiswas/sample_golang_cli/internal/dockerclient"
+	"github.com/arjunbiswas/sample_golang_cli/internal/gpu"
+	"github.com/spf13/cobra"
+)
+
+// DoctorReport is the structured result of a preflight check, printed as a
+// table or as --json for scripting.
+type DoctorReport struct {
+	DockerVersion      string `json:"docker_version"`
+	DockerAPIVersion   string `json:"docker_api_version"`
+	NvidiaRuntime      bool   `json:"nvidia_runtime"`
+	DeviceRequestsOK   bool   `json:"device_requests_supported"`
+	KernelVersion      string `json:"kernel_version"`
+	CgroupVersion      string `json:"cgroup_version"`
+	KFDPresent         bool   `json:"kfd_present"`
+	RenderNodesPresent bool   `json:"render_nodes_present"`
+	AppleSilicon       bool   `json:"apple_silicon,omitempty"`
+}
+
+var doctorJSON bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Report container-runtime capabilities for this host",
+	RunE:  runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print the report as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// deviceRequestsMinAPIVersion is the Docker Engine API version that added
+// support for HostConfig.Resources.DeviceRequests (API 1.40, Engine 19.03).
+const deviceRequestsMinAPIVersion = "1.40"
+
+// apiVersionAtLeast compares two Docker Engine API versions ("<major>.<minor>")
+// numerically, since string comparison puts "1.9" ahead of "1.40".
+func apiVersionAtLeast(version, min string) bool {
+	vMajor, vMinor, err := parseAPIVersion(version)
+	if err != nil {
+		return false
+	}
+	minMajor, minMinor, err := parseAPIVersion(min)
+	if err != nil {
+		return false
+	}
+	if vMajor != minMajor {
+		return vMajor > minMajor
+	}
+	return vMinor >= minMinor
+}
+
+// parseAPIVersion splits a Docker Engine API version into its major and
+// minor components.
+func parseAPIVersion(version string) (major, minor int, err error) {
+	parts := strings.SplitN(version, ".", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed API version %q", version)
+	}
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed API version %q: %w", version, err)
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed API version %q: %w", version, err)
+	}
+	return major, minor, nil
+}
+
+func runDoctor(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	docker, err := dockerclient.New()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+
+	report := DoctorReport{}
+
+	version, err := docker.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("doctor: %w", err)
+	}
+	report.DockerVersion = version.Version
+	report.DockerAPIVersion = version.APIVersion
+	report.DeviceRequestsOK = apiVersionAtLeast(version.APIVersion, deviceRequestsMinAPIVersion)
+
+	info, err := docker.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("doctor: %w", err)
+	}
+	_, report.NvidiaRuntime = info.Runtimes["nvidia"]
+	report.KernelVersion = info.KernelVersion
+	report.CgroupVersion = info.CgroupVersion
+
+	if _, err := os.Stat("/dev/kfd"); err == nil {
+		report.KFDPresent = true
+	}
+	if nodes, err := filepath.Glob("/dev/dri/renderD*"); err == nil && len(nodes) > 0 {
+		report.RenderNodesPresent = true
+	}
+
+	if ok, _, err := (gpu.AppleProvider{}).Detect(); err == nil {
+		report.AppleSilicon = ok
+	}
+
+	if doctorJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printDoctorTable(report)
+	return nil
+}
+
+func printDoctorTable(report DoctorReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "Docker version:\t%s\n", report.DockerVersion)
+	fmt.Fprintf(w, "Docker API version:\t%s\n", report.DockerAPIVersion)
+	fmt.Fprintf(w, "NVIDIA runtime registered:\t%t\n", report.NvidiaRuntime)
+	fmt.Fprintf(w, "DeviceRequests supported:\t%t\n", report.DeviceRequestsOK)
+	fmt.Fprintf(w, "Kernel version:\t%s\n", report.KernelVersion)
+	fmt.Fprintf(w, "Cgroup version:\t%s\n", report.CgroupVersion)
+	fmt.Fprintf(w, "/dev/kfd present:\t%t\n", report.KFDPresent)
+	fmt.Fprintf(w, "/dev/dri render nodes present:\t%t\n", report.RenderNodesPresent)
+	if report.AppleSilicon {
+		fmt.Fprintf(w, "Apple Silicon:\t%t\n", report.AppleSilicon)
+	}
+}
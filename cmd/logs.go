@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/arjunbiswas/sample_golang_cli/internal/dockerclient"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+)
+
+var logsContainerID string
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Tail the logs of a running io.net worker container",
+	RunE:  runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsContainerID, "container", "", "container ID to tail (required)")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(_ *cobra.Command, _ []string) error {
+	if logsContainerID == "" {
+		return fmt.Errorf("logs: --container is required")
+	}
+
+	ctx := context.Background()
+	docker, err := dockerclient.New()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+
+	stream, err := docker.Logs(ctx, logsContainerID, true)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	// The container runs without a TTY, so the Engine API multiplexes
+	// stdout/stderr with framing headers; demultiplex the same way
+	// internal/supervisor does.
+	_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, stream)
+	return err
+}
@@ -0,0 +1,27 @@
+// Package cmd holds the ionet CLI's cobra subcommands: run, doctor, stop,
+// logs, and version.
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configPath string
+
+var rootCmd = &cobra.Command{
+	Use:   "ionet",
+	Short: "Launch and manage the io.net worker container",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "path to the ionet config file (default: OS-appropriate config dir)")
+}
+
+// Execute runs the selected subcommand, exiting non-zero on failure.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,401 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arjunbiswas/sample_golang_cli/internal/config"
+	"github.com/arjunbiswas/sample_golang_cli/internal/dockerclient"
+	"github.com/arjunbiswas/sample_golang_cli/internal/gpu"
+	"github.com/arjunbiswas/sample_golang_cli/internal/supervisor"
+	"github.com/google/uuid"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+var repoNames = []string{"ionetcontainers/io-worker-vc", "ionetcontainers/io-worker-monitor", "ionetcontainers/io-launch"}
+var validArchChoices = []string{"x86_64", "arm64", "aarch64"}
+var validOSChoices = []string{"macOS", "Linux"}
+
+// Returns true if string is contained
+func contains(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if valid UUID is passed
+func isValidUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+func getMacInfo() bool {
+	cmd := exec.Command("sysctl", "-n", "machdep.cpu.brand_string")
+	err := cmd.Run()
+	return err == nil
+}
+
+func getPlatformArchitecture() string {
+	cmd := exec.Command("uname", "-m")
+	output, err := cmd.Output()
+	if err != nil {
+		log.Fatal("Unable to determine platform architecture")
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// ociPlatform maps the CLI's arch choice to the OCI platform struct used
+// for manifest matching, normalizing "aarch64" to "arm64" the way
+// containerd/platforms expects. The OS is always "linux": on macOS the
+// containers run inside Docker's Linux VM, so operatingSystem does not
+// change the platform.
+func ociPlatform(architecture, operatingSystem string) ocispec.Platform {
+	arch := architecture
+	if arch == "aarch64" {
+		arch = "arm64"
+	}
+	if arch == "x86_64" {
+		arch = "amd64"
+	}
+	return ocispec.Platform{OS: "linux", Architecture: arch}
+}
+
+// constructRunSpec builds the dockerclient.RunSpec for the io-launch
+// container. It resolves a native manifest for the host's platform when
+// one exists, only falling back to forcing linux/amd64 (and qemu
+// emulation) when the registry has no better match.
+func constructRunSpec(ctx context.Context, docker *dockerclient.Client, args *Arguments, architecture string, accelerator gpu.Provider, devices []gpu.DeviceInfo) dockerclient.RunSpec {
+	spec := dockerclient.RunSpec{
+		Binds:       []string{"/var/run/docker.sock:/var/run/docker.sock"},
+		NetworkMode: "host",
+		Image:       "ionetcontainers/io-launch:v0.1",
+	}
+	if args.Beta {
+		spec.Image = "ionetcontainers/io-launch-beta:v0.1"
+	}
+
+	target := ociPlatform(architecture, args.OperatingSystem)
+	resolvedRef, needsEmulation, err := docker.ResolveImageForPlatform(ctx, spec.Image, target)
+	if err != nil {
+		// Inspecting the manifest list itself failed (registry/network/auth
+		// trouble), as opposed to it succeeding with no native match: assume
+		// a native image is available rather than blocking a run that might
+		// work fine off an already-pulled image.
+		fmt.Printf("warning: could not inspect %s for platform matching (%v); assuming a native image is available\n", spec.Image, err)
+	} else {
+		spec.Image = resolvedRef
+		if needsEmulation {
+			fmt.Printf("no native %s/%s image found for %s, emulating amd64 on %s via qemu\n", target.OS, target.Architecture, spec.Image, target.Architecture)
+			spec.Platform = "linux/amd64"
+		}
+	}
+
+	spec.Env = append(spec.Env, fmt.Sprintf("ARCH=%s", architecture))
+	if args.DeviceName != "" {
+		spec.Env = append(spec.Env, fmt.Sprintf("DEVICE_NAME=%s", args.DeviceName))
+	}
+	if args.DeviceID != "" {
+		spec.Env = append(spec.Env, fmt.Sprintf("DEVICE_ID=%s", args.DeviceID))
+	}
+	if args.UserID != "" {
+		spec.Env = append(spec.Env, fmt.Sprintf("USER_ID=%s", args.UserID))
+	}
+	if args.OperatingSystem != "" {
+		spec.Env = append(spec.Env, fmt.Sprintf("OPERATING_SYSTEM=%s", args.OperatingSystem))
+	}
+	if args.UseGPUs != "" {
+		spec.Env = append(spec.Env, fmt.Sprintf("USEGPUS=%s", args.UseGPUs))
+	}
+	if accelerator != nil {
+		spec.DeviceRequests = append(spec.DeviceRequests, accelerator.DeviceRequests()...)
+		spec.ExtraArgs = append(spec.ExtraArgs, accelerator.ContainerRuntimeArgs()...)
+
+		uuids := make([]string, 0, len(devices))
+		for _, d := range devices {
+			uuids = append(uuids, d.UUID)
+		}
+		spec.Env = append(spec.Env,
+			fmt.Sprintf("ACCELERATOR_KIND=%s", accelerator.Name()),
+			fmt.Sprintf("ACCELERATOR_UUIDS=%s", strings.Join(uuids, ",")),
+		)
+		spec.Env = append(spec.Env, accelerator.Env()...)
+	}
+	if args.OperatingSystem == "macOS" {
+		macInfo, err := exec.Command("sh", "-c", "sysctl -a | grep machdep | awk -F': ' '{print \"\\\"\" $1 \"\\\": \\\"\" $2 \"\\\"\"}' | paste -sd, - | awk '{print \"{\" $0 \"}\" }'").Output()
+		if err != nil {
+			log.Fatal(err)
+		}
+		spec.Env = append(spec.Env, fmt.Sprintf("MAC_INFO=%s", strings.TrimSpace(string(macInfo))))
+	}
+	if args.Beta {
+		spec.Env = append(spec.Env, "CURRENT_LOG_LEVEL=DEBUG", "ENVIRONMENT=DEV")
+	}
+	return spec
+}
+
+// promptUntil repeatedly prints prompt and reads a line into a string via
+// fmt.Scanln until valid returns true. If a read fails (including an empty
+// line, which Scanln reports as an error), it returns "" without prompting
+// again.
+func promptUntil(prompt string, valid func(string) bool, invalidMsg string) string {
+	var value string
+	for value == "" || !valid(value) {
+		fmt.Print(prompt)
+		if _, err := fmt.Scanln(&value); err != nil {
+			return ""
+		}
+		if !valid(value) {
+			fmt.Println(invalidMsg)
+		}
+	}
+	return value
+}
+
+// Arguments is the resolved set of launch settings, merged from flags and
+// the persisted Config.
+type Arguments struct {
+	DeviceName      string
+	DeviceID        string
+	UserID          string
+	OperatingSystem string
+	UseGPUs         string
+	Architecture    string
+	Beta            bool
+}
+
+var runFlags struct {
+	deviceName      string
+	deviceID        string
+	userID          string
+	operatingSystem string
+	useGPUs         string
+	architecture    string
+	beta            bool
+	maxRestarts     int
+	gracePeriod     time.Duration
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Launch the io.net worker container",
+	RunE:  runRun,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runFlags.deviceName, "device_name", "", "")
+	runCmd.Flags().StringVar(&runFlags.deviceID, "device_id", "", "")
+	runCmd.Flags().StringVar(&runFlags.userID, "user_id", "", "")
+	runCmd.Flags().StringVar(&runFlags.operatingSystem, "operating_system", "", "")
+	runCmd.Flags().StringVar(&runFlags.useGPUs, "usegpus", "", "")
+	runCmd.Flags().StringVar(&runFlags.architecture, "arch", "", "")
+	runCmd.Flags().BoolVar(&runFlags.beta, "beta", false, "")
+	runCmd.Flags().IntVar(&runFlags.maxRestarts, "max-restarts", 3, "maximum number of times to restart the container after a non-zero exit")
+	runCmd.Flags().DurationVar(&runFlags.gracePeriod, "grace-period", 10*time.Second, "how long to wait for the container to stop gracefully before killing it")
+	rootCmd.AddCommand(runCmd)
+}
+
+func runRun(_ *cobra.Command, _ []string) error {
+	resolvedConfigPath, err := resolveConfigPath()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(resolvedConfigPath)
+	if err != nil {
+		return err
+	}
+
+	args := new(Arguments)
+
+	if runFlags.deviceName == "" {
+		args.DeviceName = cfg.DeviceName
+	} else {
+		args.DeviceName = runFlags.deviceName
+	}
+	if runFlags.deviceID == "" {
+		args.DeviceID = cfg.DeviceID
+	} else {
+		args.DeviceID = runFlags.deviceID
+	}
+	if runFlags.userID == "" {
+		args.UserID = cfg.UserID
+	} else {
+		args.UserID = runFlags.userID
+	}
+	if runFlags.operatingSystem == "" {
+		args.OperatingSystem = cfg.OperatingSystem
+	} else {
+		args.OperatingSystem = runFlags.operatingSystem
+	}
+	if runFlags.useGPUs == "" {
+		args.UseGPUs = strconv.FormatBool(cfg.UseGPUs)
+	} else {
+		args.UseGPUs = runFlags.useGPUs
+	}
+	if runFlags.architecture == "" {
+		args.Architecture = cfg.Architecture
+	} else {
+		args.Architecture = runFlags.architecture
+	}
+	args.Beta = runFlags.beta
+
+	ctx := context.Background()
+	docker, err := dockerclient.New()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+
+	if err := docker.Ping(ctx); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := docker.StopRunningContainers(ctx); err != nil {
+		return err
+	}
+
+	for _, repoName := range repoNames {
+		imageIDs, err := docker.ImageIDsSorted(ctx, repoName)
+		if err != nil {
+			return err
+		}
+		if len(imageIDs) > 1 {
+			fmt.Printf("removing stale images: %s\n", repoName)
+			for _, imageID := range imageIDs[1:] {
+				if err := docker.RemoveImage(ctx, imageID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if args.DeviceName == "" {
+		args.DeviceName = promptUntil("Enter device name: ",
+			func(v string) bool { return v != "" },
+			"Device name cannot be empty. Please enter a valid name.")
+	}
+
+	if args.DeviceID == "" || !isValidUUID(args.DeviceID) {
+		args.DeviceID = promptUntil("Enter device ID (UUID): ", isValidUUID,
+			"Invalid UUID. Please enter a proper UUID as shown on the website dashboard.")
+	}
+
+	if args.UserID == "" || !isValidUUID(args.UserID) {
+		args.UserID = promptUntil("Enter user ID (UUID): ", isValidUUID,
+			"Invalid UUID. Please enter a proper UUID as shown on the website dashboard.")
+	}
+
+	if args.OperatingSystem == "" || !contains(validOSChoices, args.OperatingSystem) {
+		args.OperatingSystem = promptUntil("Enter operating system (macOS/Linux): ",
+			func(v string) bool { return contains(validOSChoices, v) },
+			fmt.Sprintf("Invalid operating system. Please choose from %s.", strings.Join(validOSChoices, "/")))
+	}
+
+	if args.OperatingSystem == "Windows" {
+		args.Architecture = "x86_64"
+	} else if args.Architecture == "" {
+		args.Architecture = getPlatformArchitecture()
+	}
+
+	if args.OperatingSystem == "macOS" {
+		args.UseGPUs = "false"
+		fmt.Println("NOTE: If you see a warning regarding the platform mismatch (linux/amd64 vs. linux/arm64/v8), please ignore it. This is expected when running on macOS with M1/M2/M3 chips.")
+	} else if args.UseGPUs == "" || (args.UseGPUs != "true" && args.UseGPUs != "false") {
+		args.UseGPUs = promptUntil("Does this system have an NVIDIA GPU which you want to use? (true/false): ",
+			func(v string) bool { return v == "true" || v == "false" },
+			"Invalid input. Please enter 'true' or 'false'.")
+	}
+
+	var accelerator gpu.Provider
+	var accelDevices []gpu.DeviceInfo
+	switch {
+	case args.OperatingSystem == "macOS":
+		// Apple Silicon is detected for reporting (ACCELERATOR_KIND/
+		// ACCELERATOR_UUIDS) regardless of UseGPUs, which gates accelerator
+		// detection on Linux and is always false here.
+		var err error
+		accelerator, accelDevices, err = gpu.Detect()
+		if err != nil {
+			return err
+		}
+	case args.UseGPUs == "true":
+		var err error
+		accelerator, accelDevices, err = gpu.Detect()
+		if err != nil {
+			return err
+		}
+		if accelerator == nil {
+			fmt.Println("no supported accelerator found - please rerun io-setup or contact support on discord")
+			os.Exit(1)
+		}
+	}
+
+	if err := docker.Ping(ctx); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if !contains(validOSChoices, args.OperatingSystem) {
+		fmt.Printf("Error: Invalid operating system choice '%s'\n", args.OperatingSystem)
+		os.Exit(1)
+	}
+
+	if !contains(validArchChoices, args.Architecture) {
+		fmt.Printf("Platform %s - %s is not supported\n", args.Architecture, args.OperatingSystem)
+		os.Exit(1)
+	}
+
+	if args.OperatingSystem == "macOS" && !getMacInfo() {
+		fmt.Println("Your hardware isnâ€™t Mac silicon (M1, M2, M3) chips, please select proper OS and chip from website")
+		os.Exit(1)
+	}
+
+	cfg = &config.Config{
+		DeviceName:      args.DeviceName,
+		DeviceID:        args.DeviceID,
+		UserID:          args.UserID,
+		OperatingSystem: args.OperatingSystem,
+		UseGPUs:         args.UseGPUs == "true",
+		Architecture:    args.Architecture,
+	}
+	if accelerator != nil {
+		cfg.AcceleratorKind = accelerator.Name()
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := config.Save(resolvedConfigPath, cfg); err != nil {
+		return err
+	}
+
+	runSpec := constructRunSpec(ctx, docker, args, args.Architecture, accelerator, accelDevices)
+
+	sup := supervisor.New(docker, runFlags.gracePeriod, runFlags.maxRestarts)
+	containerID, err := sup.Run(ctx, runSpec)
+	if containerID != "" {
+		if saveErr := config.SaveContainerID(resolvedConfigPath, containerID); saveErr != nil {
+			fmt.Printf("warning: failed to persist container ID: %v\n", saveErr)
+		}
+	}
+	return err
+}
+
+// resolveConfigPath returns the --config override or the OS-appropriate
+// default location.
+func resolveConfigPath() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	return config.DefaultPath()
+}
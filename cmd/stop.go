@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arjunbiswas/sample_golang_cli/internal/config"
+	"github.com/arjunbiswas/sample_golang_cli/internal/dockerclient"
+	"github.com/spf13/cobra"
+)
+
+var stopGracePeriod time.Duration
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running io.net worker container",
+	RunE:  runStop,
+}
+
+func init() {
+	stopCmd.Flags().DurationVar(&stopGracePeriod, "grace-period", 10*time.Second, "how long to wait for the container to stop gracefully before killing it")
+	rootCmd.AddCommand(stopCmd)
+}
+
+func runStop(_ *cobra.Command, _ []string) error {
+	resolvedConfigPath, err := resolveConfigPath()
+	if err != nil {
+		return err
+	}
+
+	containerID, err := config.LoadContainerID(resolvedConfigPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	docker, err := dockerclient.New()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+
+	if containerID == "" {
+		// No container ID was ever persisted (or the CLI that launched it
+		// predates this feature): fall back to stopping anything running.
+		return docker.StopRunningContainers(ctx)
+	}
+
+	if err := docker.Stop(ctx, containerID, stopGracePeriod); err != nil {
+		fmt.Printf("graceful stop failed, killing container %s: %v\n", containerID, err)
+		if err := docker.Kill(ctx, containerID); err != nil {
+			return err
+		}
+	}
+	return config.ClearContainerID(resolvedConfigPath)
+}
@@ -0,0 +1,237 @@
+// Package config loads and persists the CLI's device/user settings as a
+// typed, schema-versioned JSON file.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/uuid"
+)
+
+// CurrentSchema is the schema version written by this build. Bump it and
+// add a case to migrate when the Config shape changes.
+const CurrentSchema = 2
+
+var validArchChoices = []string{"x86_64", "arm64", "aarch64"}
+var validOSChoices = []string{"macOS", "Linux"}
+
+// Config is the CLI's persisted settings, written to the path returned by
+// DefaultPath (or overridden with --config).
+type Config struct {
+	Schema          int    `json:"schema"`
+	DeviceName      string `json:"device_name"`
+	DeviceID        string `json:"device_id"`
+	UserID          string `json:"user_id"`
+	OperatingSystem string `json:"operating_system"`
+	UseGPUs         bool   `json:"use_gpus"`
+	Architecture    string `json:"architecture"`
+	AcceleratorKind string `json:"accelerator_kind,omitempty"`
+}
+
+// DefaultPath returns the OS-appropriate config file location:
+// $XDG_CONFIG_HOME/ionet/config.json on Linux (falling back to
+// ~/.config/ionet/config.json), and ~/Library/Application
+// Support/ionet/config.json on macOS.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "ionet", "config.json"), nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "ionet", "config.json"), nil
+}
+
+// Load reads and migrates the config at path. A missing file is not an
+// error: it returns a zero-value Config at CurrentSchema so first-run
+// just sees an empty config.
+func Load(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{Schema: CurrentSchema}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	migrated, err := migrate(schemaOf(raw), CurrentSchema, raw)
+	if err != nil {
+		return nil, fmt.Errorf("migrating config %s: %w", path, err)
+	}
+
+	cfg := new(Config)
+	if err := json.Unmarshal(migrated, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to path as indented JSON with 0600 permissions, creating
+// parent directories as needed.
+func Save(path string, cfg *Config) error {
+	cfg.Schema = CurrentSchema
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating config directory for %s: %w", path, err)
+	}
+
+	raw, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		return fmt.Errorf("writing config %s: %w", path, err)
+	}
+	return nil
+}
+
+// containerIDPath returns the sibling file next to the config that holds
+// the most recently launched container's ID, so `ionet stop` can find and
+// terminate it even after the CLI that started it has exited.
+func containerIDPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "container_id")
+}
+
+// SaveContainerID persists containerID next to the config at configPath.
+func SaveContainerID(configPath, containerID string) error {
+	path := containerIDPath(configPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating config directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(containerID), 0600); err != nil {
+		return fmt.Errorf("writing container ID to %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadContainerID reads back the container ID saved by SaveContainerID. It
+// returns an empty string, not an error, if no container has been launched
+// yet (or its ID was already consumed).
+func LoadContainerID(configPath string) (string, error) {
+	raw, err := os.ReadFile(containerIDPath(configPath))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading container ID: %w", err)
+	}
+	return string(raw), nil
+}
+
+// ClearContainerID removes the persisted container ID once it's no longer
+// running.
+func ClearContainerID(configPath string) error {
+	err := os.Remove(containerIDPath(configPath))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing container ID: %w", err)
+	}
+	return nil
+}
+
+// schemaOf extracts the "schema" field from raw config JSON, treating the
+// pre-versioning map[string]interface{} format (no "schema" key at all) as
+// schema 1.
+func schemaOf(raw []byte) int {
+	var probe struct {
+		Schema int `json:"schema"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil || probe.Schema == 0 {
+		return 1
+	}
+	return probe.Schema
+}
+
+// migrate upgrades raw config JSON from schema `from` to schema `to`,
+// applying each intermediate step in turn.
+func migrate(from, to int, raw []byte) ([]byte, error) {
+	if from > to {
+		return nil, fmt.Errorf("config schema %d is newer than supported schema %d", from, to)
+	}
+
+	data := raw
+	for schema := from; schema < to; schema++ {
+		var err error
+		switch schema {
+		case 1:
+			data, err = migrateV1ToV2(data)
+		default:
+			return nil, fmt.Errorf("no migration path from schema %d", schema)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// migrateV1ToV2 upgrades the original untyped ionet_device_cache.txt map
+// (string values for every field, including "usegpus" as "true"/"false")
+// into the typed schema 2 shape.
+func migrateV1ToV2(raw []byte) ([]byte, error) {
+	var old map[string]interface{}
+	if err := json.Unmarshal(raw, &old); err != nil {
+		return nil, fmt.Errorf("parsing schema 1 config: %w", err)
+	}
+
+	str := func(key string) string {
+		v, _ := old[key].(string)
+		return v
+	}
+
+	cfg := Config{
+		Schema:          2,
+		DeviceName:      str("device_name"),
+		DeviceID:        str("device_id"),
+		UserID:          str("user_id"),
+		OperatingSystem: str("operating_system"),
+		UseGPUs:         str("usegpus") == "true",
+		Architecture:    str("arch"),
+	}
+	return json.Marshal(cfg)
+}
+
+// Validate checks the invariants a Config must satisfy before it's used to
+// drive a docker action: well-formed UUIDs, a recognized architecture/OS,
+// and OS/GPU combinations that make sense (e.g. macOS never uses GPUs).
+func (c *Config) Validate() error {
+	if c.DeviceID != "" {
+		if _, err := uuid.Parse(c.DeviceID); err != nil {
+			return fmt.Errorf("device_id %q is not a valid UUID", c.DeviceID)
+		}
+	}
+	if c.UserID != "" {
+		if _, err := uuid.Parse(c.UserID); err != nil {
+			return fmt.Errorf("user_id %q is not a valid UUID", c.UserID)
+		}
+	}
+	if c.OperatingSystem != "" && !contains(validOSChoices, c.OperatingSystem) {
+		return fmt.Errorf("operating_system %q must be one of %v", c.OperatingSystem, validOSChoices)
+	}
+	if c.Architecture != "" && !contains(validArchChoices, c.Architecture) {
+		return fmt.Errorf("architecture %q must be one of %v", c.Architecture, validArchChoices)
+	}
+	if c.OperatingSystem == "macOS" && c.UseGPUs {
+		return fmt.Errorf("use_gpus cannot be true when operating_system is macOS")
+	}
+	return nil
+}
+
+func contains(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchemaOf(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want int
+	}{
+		{"no schema field", `{"device_id":"abc"}`, 1},
+		{"schema 2", `{"schema":2}`, 2},
+		{"invalid json", `not json`, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := schemaOf([]byte(tc.raw)); got != tc.want {
+				t.Errorf("schemaOf(%q) = %d, want %d", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMigrateV1ToV2(t *testing.T) {
+	v1 := `{
+		"device_name": "rig-1",
+		"device_id": "11111111-1111-1111-1111-111111111111",
+		"user_id": "22222222-2222-2222-2222-222222222222",
+		"operating_system": "Linux",
+		"usegpus": "true",
+		"arch": "x86_64"
+	}`
+
+	migrated, err := migrateV1ToV2([]byte(v1))
+	if err != nil {
+		t.Fatalf("migrateV1ToV2: %v", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
+		t.Fatalf("unmarshaling migrated config: %v", err)
+	}
+
+	want := Config{
+		Schema:          2,
+		DeviceName:      "rig-1",
+		DeviceID:        "11111111-1111-1111-1111-111111111111",
+		UserID:          "22222222-2222-2222-2222-222222222222",
+		OperatingSystem: "Linux",
+		UseGPUs:         true,
+		Architecture:    "x86_64",
+	}
+	if cfg != want {
+		t.Errorf("migrateV1ToV2() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	if _, err := migrate(3, 2, []byte(`{}`)); err == nil {
+		t.Error("migrate(3, 2, ...) should error: schema newer than supported")
+	}
+	if _, err := migrate(2, 2, []byte(`{"schema":2}`)); err != nil {
+		t.Errorf("migrate(2, 2, ...) should be a no-op, got error: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"zero value is valid", Config{}, false},
+		{"bad device id", Config{DeviceID: "not-a-uuid"}, true},
+		{"bad operating system", Config{OperatingSystem: "Plan9"}, true},
+		{"bad architecture", Config{Architecture: "sparc"}, true},
+		{"macOS with GPUs", Config{OperatingSystem: "macOS", UseGPUs: true}, true},
+		{"linux with GPUs", Config{OperatingSystem: "Linux", UseGPUs: true}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
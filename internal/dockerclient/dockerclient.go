@@ -0,0 +1,255 @@
+// Package dockerclient talks to the Docker daemon through the Engine SDK.
+package dockerclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Client wraps the Docker Engine SDK client with the small set of
+// operations this CLI needs.
+type Client struct {
+	cli *client.Client
+}
+
+// New creates a Client from the environment (DOCKER_HOST, DOCKER_TLS_VERIFY,
+// etc.), matching the docker CLI's own resolution rules.
+func New() (*Client, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("creating docker client: %w", err)
+	}
+	return &Client{cli: cli}, nil
+}
+
+// Close releases the underlying connection to the daemon.
+func (c *Client) Close() error {
+	return c.cli.Close()
+}
+
+// Ping reports whether the Docker daemon is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.cli.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("docker daemon is not running: %w", err)
+	}
+	return nil
+}
+
+// ImageIDsSorted returns the IDs of every image whose repository contains
+// repoName, newest first.
+func (c *Client) ImageIDsSorted(ctx context.Context, repoName string) ([]string, error) {
+	images, err := c.cli.ImageList(ctx, types.ImageListOptions{
+		Filters: filters.NewArgs(filters.Arg("reference", "*"+repoName+"*")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing images for %s: %w", repoName, err)
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Created > images[j].Created
+	})
+
+	ids := make([]string, 0, len(images))
+	for _, img := range images {
+		ids = append(ids, img.ID)
+	}
+	return ids, nil
+}
+
+// RemoveImage deletes a single image by ID.
+func (c *Client) RemoveImage(ctx context.Context, imageID string) error {
+	_, err := c.cli.ImageRemove(ctx, imageID, types.ImageRemoveOptions{})
+	if err != nil {
+		return fmt.Errorf("removing image %s: %w", imageID, err)
+	}
+	return nil
+}
+
+// StopRunningContainers stops every running container, falling back to a
+// kill if the graceful stop fails.
+func (c *Client) StopRunningContainers(ctx context.Context) error {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing running containers: %w", err)
+	}
+
+	for _, ctr := range containers {
+		if err := c.cli.ContainerStop(ctx, ctr.ID, container.StopOptions{}); err != nil {
+			if killErr := c.cli.ContainerKill(ctx, ctr.ID, "SIGKILL"); killErr != nil {
+				return fmt.Errorf("stopping container %s: %w (kill also failed: %v)", ctr.ID, err, killErr)
+			}
+		}
+	}
+	return nil
+}
+
+// RunSpec describes the container the CLI wants to launch.
+type RunSpec struct {
+	Image          string
+	Env            []string
+	Binds          []string
+	NetworkMode    string
+	Platform       string
+	DeviceRequests []container.DeviceRequest
+	// ExtraArgs holds `--device=<path>` entries for accelerators the
+	// daemon doesn't support via DeviceRequests (e.g. ROCm's /dev/kfd and
+	// /dev/dri/renderD* nodes); see internal/gpu.
+	ExtraArgs []string
+}
+
+// deviceMappings parses the `--device=<path>` entries in ExtraArgs into
+// Engine SDK DeviceMapping entries.
+func deviceMappings(extraArgs []string) []container.DeviceMapping {
+	var mappings []container.DeviceMapping
+	for _, arg := range extraArgs {
+		path := strings.TrimPrefix(arg, "--device=")
+		mappings = append(mappings, container.DeviceMapping{
+			PathOnHost:        path,
+			PathInContainer:   path,
+			CgroupPermissions: "rwm",
+		})
+	}
+	return mappings
+}
+
+// Run pulls spec.Image (unlike the docker CLI, ContainerCreate never pulls
+// on our behalf), then creates and starts a detached container from spec,
+// returning its ID.
+func (c *Client) Run(ctx context.Context, spec RunSpec) (string, error) {
+	if err := c.pullImage(ctx, spec.Image, spec.Platform); err != nil {
+		return "", err
+	}
+
+	hostConfig := &container.HostConfig{
+		Binds:       spec.Binds,
+		NetworkMode: container.NetworkMode(spec.NetworkMode),
+		Resources: container.Resources{
+			DeviceRequests: spec.DeviceRequests,
+			Devices:        deviceMappings(spec.ExtraArgs),
+		},
+	}
+
+	var platform *ocispec.Platform
+	if spec.Platform != "" {
+		p, err := platforms.Parse(spec.Platform)
+		if err != nil {
+			return "", fmt.Errorf("parsing platform %q: %w", spec.Platform, err)
+		}
+		platform = &p
+	}
+
+	resp, err := c.cli.ContainerCreate(ctx, &container.Config{
+		Image: spec.Image,
+		Env:   spec.Env,
+	}, hostConfig, nil, platform, "")
+	if err != nil {
+		return "", fmt.Errorf("creating container from %s: %w", spec.Image, err)
+	}
+
+	if err := c.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return "", fmt.Errorf("starting container %s: %w", resp.ID, err)
+	}
+	return resp.ID, nil
+}
+
+// pullImage pulls ref for platform (the daemon's default platform if
+// empty), draining the pull's progress stream since we only care whether
+// it succeeded.
+func (c *Client) pullImage(ctx context.Context, ref, platform string) error {
+	out, err := c.cli.ImagePull(ctx, ref, image.PullOptions{Platform: platform})
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w", ref, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(io.Discard, out); err != nil {
+		return fmt.Errorf("pulling image %s: %w", ref, err)
+	}
+	return nil
+}
+
+// Version returns the daemon's version and negotiated API version info, for
+// `doctor` to report.
+func (c *Client) Version(ctx context.Context) (types.Version, error) {
+	v, err := c.cli.ServerVersion(ctx)
+	if err != nil {
+		return types.Version{}, fmt.Errorf("fetching docker version: %w", err)
+	}
+	return v, nil
+}
+
+// Info returns the daemon's system info (runtimes, cgroup version, kernel
+// version, etc.), for `doctor` to report.
+func (c *Client) Info(ctx context.Context) (system.Info, error) {
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return system.Info{}, fmt.Errorf("fetching docker info: %w", err)
+	}
+	return info, nil
+}
+
+// Logs returns the combined stdout/stderr log stream for a container. The
+// caller is responsible for demultiplexing it (see internal/supervisor) and
+// closing it when done.
+func (c *Client) Logs(ctx context.Context, containerID string, follow bool) (io.ReadCloser, error) {
+	return c.cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+}
+
+// Stop asks the container to stop gracefully, waiting up to grace before
+// the daemon sends SIGKILL itself.
+func (c *Client) Stop(ctx context.Context, containerID string, grace time.Duration) error {
+	seconds := int(grace.Seconds())
+	if err := c.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &seconds}); err != nil {
+		return fmt.Errorf("stopping container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// Kill forcefully terminates a container with SIGKILL.
+func (c *Client) Kill(ctx context.Context, containerID string) error {
+	if err := c.cli.ContainerKill(ctx, containerID, "SIGKILL"); err != nil {
+		return fmt.Errorf("killing container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// Remove deletes an exited container, e.g. one the supervisor is about to
+// replace after a crash restart.
+func (c *Client) Remove(ctx context.Context, containerID string) error {
+	if err := c.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{}); err != nil {
+		return fmt.Errorf("removing container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// Wait blocks until the container exits (or ctx is canceled) and returns
+// its exit code.
+func (c *Client) Wait(ctx context.Context, containerID string) (int64, error) {
+	statusCh, errCh := c.cli.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return 0, fmt.Errorf("waiting for container %s: %w", containerID, err)
+	case status := <-statusCh:
+		return status.StatusCode, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
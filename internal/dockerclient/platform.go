@@ -0,0 +1,73 @@
+package dockerclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ResolveImageForPlatform inspects ref's manifest list and picks the
+// descriptor whose platform matches target, normalizing architecture
+// variants the same way Moby does (e.g. falling back to a variant-less
+// arm64 match when no `v8`-tagged descriptor exists). It returns the
+// reference to pull and whether the host will need to emulate (because no
+// native descriptor was found and the daemon will fall back to qemu).
+func (c *Client) ResolveImageForPlatform(ctx context.Context, ref string, target ocispec.Platform) (resolvedRef string, needsEmulation bool, err error) {
+	descriptors, err := c.manifestPlatforms(ctx, ref)
+	if err != nil {
+		return "", false, fmt.Errorf("inspecting manifest list for %s: %w", ref, err)
+	}
+
+	if hasNativeMatch(descriptors, target) {
+		return ref, false, nil
+	}
+
+	// No native descriptor: the daemon will have to emulate via qemu.
+	return ref, true, nil
+}
+
+// hasNativeMatch reports whether descriptors contains a platform matching
+// target, falling back to Moby's variant-less match (an arm64 target with
+// no variant still matches a descriptor published with one, and vice
+// versa) when no exact match exists.
+func hasNativeMatch(descriptors []ocispec.Platform, target ocispec.Platform) bool {
+	matcher := platforms.NewMatcher(target)
+	for _, d := range descriptors {
+		if matcher.Match(d) {
+			return true
+		}
+	}
+
+	normalized := target
+	normalized.Variant = ""
+	fallbackMatcher := platforms.NewMatcher(normalized)
+	for _, d := range descriptors {
+		d.Variant = ""
+		if fallbackMatcher.Match(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestPlatforms returns the platform of every descriptor in ref's
+// manifest list via the Engine SDK's DistributionInspect call, which talks
+// to the registry v2 API on our behalf.
+func (c *Client) manifestPlatforms(ctx context.Context, ref string) ([]ocispec.Platform, error) {
+	inspect, err := c.cli.DistributionInspect(ctx, ref, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ocispec.Platform
+	for _, d := range inspect.Platforms {
+		result = append(result, ocispec.Platform{
+			OS:           d.OS,
+			Architecture: d.Architecture,
+			Variant:      d.Variant,
+		})
+	}
+	return result, nil
+}
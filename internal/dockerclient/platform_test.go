@@ -0,0 +1,34 @@
+package dockerclient
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestHasNativeMatch(t *testing.T) {
+	descriptors := []ocispec.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}
+
+	cases := []struct {
+		name   string
+		target ocispec.Platform
+		want   bool
+	}{
+		{"exact amd64 match", ocispec.Platform{OS: "linux", Architecture: "amd64"}, true},
+		{"exact arm64/v8 match", ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}, true},
+		{"variant-less arm64 falls back to v8 descriptor", ocispec.Platform{OS: "linux", Architecture: "arm64"}, true},
+		{"no matching architecture", ocispec.Platform{OS: "linux", Architecture: "386"}, false},
+		{"no matching OS", ocispec.Platform{OS: "windows", Architecture: "amd64"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := hasNativeMatch(descriptors, tc.target); got != tc.want {
+				t.Errorf("hasNativeMatch(%+v) = %v, want %v", tc.target, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package gpu
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// AMDProvider detects ROCm-capable AMD GPUs by checking for the kernel
+// fusion driver and render nodes, the standard ROCm passthrough pattern
+// (`--device=/dev/kfd --device=/dev/dri`).
+type AMDProvider struct{}
+
+func (AMDProvider) Name() string { return "amd" }
+
+func (p AMDProvider) Detect() (bool, []DeviceInfo, error) {
+	if _, err := os.Stat("/dev/kfd"); err != nil {
+		return false, nil, nil
+	}
+
+	renderNodes, err := filepath.Glob("/dev/dri/renderD*")
+	if err != nil || len(renderNodes) == 0 {
+		return false, nil, nil
+	}
+
+	devices := make([]DeviceInfo, 0, len(renderNodes))
+	for _, node := range renderNodes {
+		devices = append(devices, DeviceInfo{Kind: p.Name(), Name: node})
+	}
+	return true, devices, nil
+}
+
+// ContainerRuntimeArgs mounts the kfd control device and every render node
+// into the container, since ROCm isn't exposed through Docker's
+// DeviceRequests mechanism.
+func (AMDProvider) ContainerRuntimeArgs() []string {
+	args := []string{"--device=/dev/kfd"}
+	renderNodes, _ := filepath.Glob("/dev/dri/renderD*")
+	for _, node := range renderNodes {
+		args = append(args, "--device="+node)
+	}
+	return args
+}
+
+func (AMDProvider) DeviceRequests() []container.DeviceRequest {
+	return nil
+}
+
+func (AMDProvider) Env() []string {
+	return nil
+}
@@ -0,0 +1,40 @@
+package gpu
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// AppleProvider detects Apple Silicon's Metal GPU. Docker containers on
+// macOS run inside a Linux VM and can't access Metal directly; detection
+// here is purely informational, used to report ACCELERATOR_KIND to the
+// container. Detect tries it after the NVIDIA and AMD providers.
+type AppleProvider struct{}
+
+func (AppleProvider) Name() string { return "apple" }
+
+func (p AppleProvider) Detect() (bool, []DeviceInfo, error) {
+	output, err := exec.Command("sysctl", "-n", "machdep.cpu.brand_string").Output()
+	if err != nil {
+		return false, nil, nil
+	}
+	brand := strings.TrimSpace(string(output))
+	if !strings.Contains(brand, "Apple") {
+		return false, nil, nil
+	}
+	return true, []DeviceInfo{{Kind: p.Name(), Name: brand}}, nil
+}
+
+func (AppleProvider) ContainerRuntimeArgs() []string {
+	return nil
+}
+
+func (AppleProvider) DeviceRequests() []container.DeviceRequest {
+	return nil
+}
+
+func (AppleProvider) Env() []string {
+	return nil
+}
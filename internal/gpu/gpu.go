@@ -0,0 +1,62 @@
+// Package gpu detects and describes accelerator hardware (NVIDIA, AMD,
+// Apple Silicon) and translates what it finds into the container runtime
+// arguments needed to pass that hardware through to the launched
+// container.
+package gpu
+
+import "github.com/docker/docker/api/types/container"
+
+// DeviceInfo describes a single accelerator device found on the host.
+type DeviceInfo struct {
+	Kind string // e.g. "nvidia", "amd", "apple"
+	UUID string
+	Name string
+}
+
+// Provider detects one vendor's accelerators and knows how to expose them
+// to a launched container.
+type Provider interface {
+	// Name identifies the provider, e.g. "nvidia", "amd", "apple".
+	Name() string
+
+	// Detect reports whether this provider's hardware is present and, if
+	// so, which devices were found.
+	Detect() (bool, []DeviceInfo, error)
+
+	// ContainerRuntimeArgs returns extra docker run-style arguments (device
+	// mounts, runtime flags) needed for vendors that aren't expressed via
+	// DeviceRequests, e.g. AMD's /dev/kfd and /dev/dri/renderD* mounts.
+	ContainerRuntimeArgs() []string
+
+	// DeviceRequests returns the Engine SDK DeviceRequests entries for
+	// vendors the daemon natively understands (currently just NVIDIA).
+	DeviceRequests() []container.DeviceRequest
+
+	// Env returns extra environment variables the launched container needs
+	// to see this vendor's hardware, e.g. NVIDIA_VISIBLE_DEVICES.
+	Env() []string
+}
+
+// Providers lists every registered provider in detection order. The run
+// command iterates this (via Detect) when UseGPUs == "true" and on macOS,
+// and uses the first one that detects hardware.
+var Providers = []Provider{
+	NvidiaProvider{},
+	AMDProvider{},
+	AppleProvider{},
+}
+
+// Detect runs every registered provider and returns the first one that
+// reports present hardware, along with the devices it found.
+func Detect() (Provider, []DeviceInfo, error) {
+	for _, p := range Providers {
+		ok, devices, err := p.Detect()
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return p, devices, nil
+		}
+	}
+	return nil, nil, nil
+}
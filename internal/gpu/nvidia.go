@@ -0,0 +1,69 @@
+package gpu
+
+import (
+	"encoding/xml"
+	"os/exec"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// NvidiaProvider detects NVIDIA GPUs by parsing `nvidia-smi -q -x`.
+type NvidiaProvider struct{}
+
+func (NvidiaProvider) Name() string { return "nvidia" }
+
+type nvidiaSMILog struct {
+	XMLName xml.Name    `xml:"nvidia_smi_log"`
+	GPUs    []nvidiaGPU `xml:"gpu"`
+}
+
+type nvidiaGPU struct {
+	UUID        string `xml:"uuid"`
+	ProductName string `xml:"product_name"`
+}
+
+// Detect shells out to `nvidia-smi -q -x` and parses the XML report; an
+// empty or unparsable report means no NVIDIA hardware (or driver) is
+// present, rather than treating any non-zero exit as a hard failure.
+func (p NvidiaProvider) Detect() (bool, []DeviceInfo, error) {
+	output, err := exec.Command("nvidia-smi", "-q", "-x").Output()
+	if err != nil {
+		return false, nil, nil
+	}
+
+	var log nvidiaSMILog
+	if err := xml.Unmarshal(output, &log); err != nil || len(log.GPUs) == 0 {
+		return false, nil, nil
+	}
+
+	devices := make([]DeviceInfo, 0, len(log.GPUs))
+	for _, g := range log.GPUs {
+		devices = append(devices, DeviceInfo{Kind: p.Name(), UUID: g.UUID, Name: g.ProductName})
+	}
+	return true, devices, nil
+}
+
+func (NvidiaProvider) ContainerRuntimeArgs() []string {
+	return nil
+}
+
+// DeviceRequests grants the container access to every NVIDIA GPU on the
+// host, equivalent to the CLI's `--gpus all`.
+func (NvidiaProvider) DeviceRequests() []container.DeviceRequest {
+	return []container.DeviceRequest{
+		{
+			Driver:       "nvidia",
+			Count:        -1,
+			Capabilities: [][]string{{"gpu", "nvidia", "compute", "utility"}},
+		},
+	}
+}
+
+// Env returns the env vars the nvidia-container-runtime hook uses to decide
+// which driver capabilities and devices to expose, alongside DeviceRequests.
+func (NvidiaProvider) Env() []string {
+	return []string{
+		"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+		"NVIDIA_VISIBLE_DEVICES=all",
+	}
+}
@@ -0,0 +1,127 @@
+// Package supervisor manages the full lifecycle of the launched io.net
+// worker container: streaming its logs, reacting to Ctrl-C with a graceful
+// stop, and restarting it with backoff if it crashes.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/arjunbiswas/sample_golang_cli/internal/dockerclient"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// defaultGracePeriod is how long Supervisor waits for a container to stop
+// on its own after SIGINT/SIGTERM before it sends SIGKILL.
+const defaultGracePeriod = 10 * time.Second
+
+// initialBackoff and maxBackoff bound the delay between restart attempts.
+const (
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Supervisor creates a container, streams its logs, and keeps it running
+// until the process is asked to stop.
+type Supervisor struct {
+	Docker      *dockerclient.Client
+	GracePeriod time.Duration
+	MaxRestarts int
+}
+
+// New returns a Supervisor with the given restart budget. grace <= 0 uses
+// defaultGracePeriod.
+func New(docker *dockerclient.Client, grace time.Duration, maxRestarts int) *Supervisor {
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+	return &Supervisor{Docker: docker, GracePeriod: grace, MaxRestarts: maxRestarts}
+}
+
+// Run creates and starts spec, streams its logs to stdout/stderr, and
+// blocks until the container exits for good: either the user interrupts it
+// (SIGINT/SIGTERM triggers a graceful stop), or it exits non-zero more
+// than MaxRestarts times in a row. It returns the ID of the last container
+// it launched.
+func (s *Supervisor) Run(ctx context.Context, spec dockerclient.RunSpec) (string, error) {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		containerID, err := s.Docker.Run(ctx, spec)
+		if err != nil {
+			return "", fmt.Errorf("starting container: %w", err)
+		}
+		fmt.Printf("started container %s\n", containerID)
+
+		go s.streamLogs(ctx, containerID)
+
+		exitCode, waitErr := s.Docker.Wait(ctx, containerID)
+
+		if ctx.Err() != nil {
+			// Interrupted: stop gracefully, then force-kill if it doesn't
+			// exit within GracePeriod.
+			s.gracefulStop(containerID)
+			return containerID, nil
+		}
+
+		if waitErr != nil {
+			return containerID, waitErr
+		}
+
+		if exitCode == 0 {
+			return containerID, nil
+		}
+
+		if attempt >= s.MaxRestarts {
+			return containerID, fmt.Errorf("container %s exited with code %d after %d restarts", containerID, exitCode, attempt)
+		}
+
+		fmt.Printf("container %s exited with code %d, restarting in %s (attempt %d/%d)\n", containerID, exitCode, backoff, attempt+1, s.MaxRestarts)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return containerID, nil
+		}
+		if err := s.Docker.Remove(ctx, containerID); err != nil {
+			fmt.Printf("failed to remove exited container %s: %v\n", containerID, err)
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// gracefulStop stops containerID, giving it GracePeriod before killing it.
+func (s *Supervisor) gracefulStop(containerID string) {
+	stopCtx, cancel := context.WithTimeout(context.Background(), s.GracePeriod+5*time.Second)
+	defer cancel()
+
+	if err := s.Docker.Stop(stopCtx, containerID, s.GracePeriod); err != nil {
+		fmt.Printf("graceful stop failed, killing container %s: %v\n", containerID, err)
+		if killErr := s.Docker.Kill(stopCtx, containerID); killErr != nil {
+			fmt.Printf("failed to kill container %s: %v\n", containerID, killErr)
+		}
+	}
+}
+
+// streamLogs tails containerID's combined log stream, demultiplexing
+// stdout/stderr via stdcopy the way `docker logs` does internally.
+func (s *Supervisor) streamLogs(ctx context.Context, containerID string) {
+	stream, err := s.Docker.Logs(ctx, containerID, true)
+	if err != nil {
+		fmt.Printf("failed to attach to logs for %s: %v\n", containerID, err)
+		return
+	}
+	defer stream.Close()
+
+	if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, stream); err != nil && ctx.Err() == nil {
+		fmt.Printf("log stream for %s ended: %v\n", containerID, err)
+	}
+}